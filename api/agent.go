@@ -0,0 +1,30 @@
+package api
+
+// Leave is used to trigger a graceful leave and shutdown of the agent.
+func (a *Agent) Leave() error {
+	r := a.c.newRequest("PUT", "/v1/agent/leave")
+	_, resp, err := a.c.doRequest(r)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return nil
+}
+
+// LeavePrune is like Leave, but additionally sets the prune query
+// parameter, asking the agent to erase the leaving node from the member
+// list immediately rather than leaving it in the "left" state to be
+// reaped later, mirroring serf's `force-leave --prune`. Erasure only
+// happens if the agent that handles the request actually implements the
+// prune parameter on its leave endpoint; this client-side change alone
+// doesn't add that server-side behavior.
+func (a *Agent) LeavePrune() error {
+	r := a.c.newRequest("PUT", "/v1/agent/leave")
+	r.params.Set("prune", "1")
+	_, resp, err := a.c.doRequest(r)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return nil
+}
@@ -11,6 +11,9 @@ import (
 // the Consul agent to gracefully leave the cluster
 type LeaveCommand struct {
 	base.Command
+
+	// flags
+	prune bool
 }
 
 func (c *LeaveCommand) Help() string {
@@ -19,6 +22,15 @@ Usage: consul leave [options]
 
   Causes the agent to gracefully leave the Consul cluster and shutdown.
 
+Leave Options:
+
+  -prune
+    Also request that the agent erase the leaving node's entry from the
+    member list immediately, rather than leaving it in the "left" state
+    to be reaped later. Use this when decommissioning a host. This
+    requires the agent handling the request to support the prune
+    parameter on its leave endpoint.
+
 ` + c.Command.Help()
 
 	return strings.TrimSpace(helpText)
@@ -26,6 +38,8 @@ Usage: consul leave [options]
 
 func (c *LeaveCommand) Run(args []string) int {
 	f := c.Command.NewFlagSet(c)
+	f.BoolVar(&c.prune, "prune", false, "erase the leaving node from the "+
+		"member list immediately instead of waiting for it to be reaped")
 	if err := c.Command.Parse(args); err != nil {
 		return 1
 	}
@@ -42,9 +56,16 @@ func (c *LeaveCommand) Run(args []string) int {
 		return 1
 	}
 
-	if err := client.Agent().Leave(); err != nil {
-		c.UI.Error(fmt.Sprintf("Error leaving: %s", err))
-		return 1
+	if c.prune {
+		if err := client.Agent().LeavePrune(); err != nil {
+			c.UI.Error(fmt.Sprintf("Error leaving: %s", err))
+			return 1
+		}
+	} else {
+		if err := client.Agent().Leave(); err != nil {
+			c.UI.Error(fmt.Sprintf("Error leaving: %s", err))
+			return 1
+		}
 	}
 
 	c.UI.Output("Graceful leave complete")
@@ -0,0 +1,128 @@
+package retry
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock abstracts the time source used by Retryers so that tests can
+// substitute a mock clock that advances independent of wall time. The
+// zero value of a Retryer uses defaultClock, which behaves exactly like
+// the time package.
+type Clock interface {
+	Now() time.Time
+	NewTicker(d time.Duration) Ticker
+}
+
+// Ticker delivers a single tick on C after the duration it was created
+// with, similar to time.Timer.
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// defaultClock is used by Retryers whose Clock field is left unset.
+var defaultClock Clock = realClock{}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+func (realClock) NewTicker(d time.Duration) Ticker {
+	return &realTicker{t: time.NewTimer(d)}
+}
+
+type realTicker struct {
+	t *time.Timer
+}
+
+func (r *realTicker) C() <-chan time.Time { return r.t.C }
+func (r *realTicker) Stop()               { r.t.Stop() }
+
+// MockClock is a Clock whose Now and tick channels are driven manually by
+// calling Advance instead of wall-clock time. This lets the retry package,
+// and any consumer's tests, run in microseconds instead of waiting on
+// real timers.
+type MockClock struct {
+	mu         sync.Mutex
+	now        time.Time
+	tickers    []*mockTicker
+	registered int
+}
+
+// NewMockClock returns a MockClock starting at the Unix epoch.
+func NewMockClock() *MockClock {
+	return &MockClock{now: time.Unix(0, 0)}
+}
+
+func (c *MockClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *MockClock) NewTicker(d time.Duration) Ticker {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &mockTicker{deadline: c.now.Add(d), ch: make(chan time.Time, 1)}
+	if !c.now.Before(t.deadline) {
+		// The requested duration has already elapsed as of the current
+		// mock time (e.g. d is 0): fire right away instead of waiting
+		// for a later Advance that may never cross this deadline again.
+		t.fired = true
+		t.ch <- c.now
+	} else {
+		c.tickers = append(c.tickers, t)
+	}
+	c.registered++
+	return t
+}
+
+// Advance moves the mock clock forward by d, firing any outstanding
+// tickers whose deadline has been reached.
+func (c *MockClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+
+	live := c.tickers[:0]
+	for _, t := range c.tickers {
+		if !t.fired && !c.now.Before(t.deadline) {
+			t.fired = true
+			t.ch <- c.now
+			continue
+		}
+		live = append(live, t)
+	}
+	c.tickers = live
+}
+
+// BlockUntil blocks until at least n tickers have been registered via
+// NewTicker since the clock was created, or timeout elapses, in which
+// case it returns false. This gives a test a way to wait for a
+// concurrently running retry loop to actually reach its wait point
+// before calling Advance, instead of racing it.
+func (c *MockClock) BlockUntil(n int, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for {
+		c.mu.Lock()
+		registered := c.registered
+		c.mu.Unlock()
+		if registered >= n {
+			return true
+		}
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+type mockTicker struct {
+	deadline time.Time
+	fired    bool
+	ch       chan time.Time
+}
+
+func (t *mockTicker) C() <-chan time.Time { return t.ch }
+func (t *mockTicker) Stop()               {}
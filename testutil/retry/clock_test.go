@@ -0,0 +1,55 @@
+package retry
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMockClock_advancesWithoutSleeping(t *testing.T) {
+	clock := NewMockClock()
+	r := &Timer{Timeout: time.Hour, Wait: time.Minute, Clock: clock}
+
+	if !r.NextOr(func() { t.Fatal("fail should not be called yet") }) {
+		t.Fatal("expected the first call to start the timer and return true")
+	}
+
+	failed := false
+	done := make(chan bool, 1)
+	go func() { done <- r.NextOr(func() { failed = true }) }()
+
+	if !clock.BlockUntil(1, time.Second) {
+		t.Fatal("ticker was never registered by the concurrent NextOr call")
+	}
+	clock.Advance(time.Minute)
+
+	select {
+	case ok := <-done:
+		if !ok {
+			t.Fatal("expected NextOr to return true once the ticker fired")
+		}
+		if failed {
+			t.Fatal("fail should not be called")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("NextOr did not return after the mock clock advanced")
+	}
+}
+
+func TestMockClock_timesOutWithoutSleeping(t *testing.T) {
+	clock := NewMockClock()
+	r := &Timer{Timeout: time.Minute, Wait: time.Second, Clock: clock}
+
+	if !r.NextOr(func() { t.Fatal("fail should not be called yet") }) {
+		t.Fatal("expected the first call to return true")
+	}
+
+	clock.Advance(time.Hour)
+
+	failed := false
+	if r.NextOr(func() { failed = true }) {
+		t.Fatal("expected NextOr to return false once the timeout passed")
+	}
+	if !failed {
+		t.Fatal("expected fail to be called once the timeout passed")
+	}
+}
@@ -0,0 +1,78 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Config wraps a Retryer with an error classifier, so callers can retry
+// only on transient errors (connection refused, 5xx, a leader election in
+// progress) and bail out immediately on permanent ones (4xx, auth
+// failures) instead of retrying blindly on any error until the whole
+// budget elapses.
+type Config struct {
+	// Retryer controls how many attempts are made and how long to wait
+	// between them.
+	Retryer Retryer
+
+	// ShouldRetry classifies an error returned by the operation. A nil
+	// ShouldRetry retries on any non-nil error.
+	ShouldRetry func(err error) bool
+
+	// OnRetry, if set, is called after each failed attempt, before the
+	// next one is scheduled.
+	OnRetry func(attempt int, err error)
+}
+
+// Run calls f until it returns nil, ShouldRetry classifies its error as
+// permanent, the Retryer gives up, or ctx is done. It returns nil on
+// success, or an *Error wrapping every error seen across all attempts.
+func (c Config) Run(ctx context.Context, f func(ctx context.Context) error) error {
+	var errs []error
+	attempt := 0
+	for {
+		// Gate every attempt, including the first, through the Retryer,
+		// matching the run() loop in retry.go. A Retryer's first call
+		// only starts its clock and never waits, so this costs nothing
+		// on the first attempt but ensures the wait between the first
+		// and second attempts is actually honored.
+		gaveUp := false
+		if !c.Retryer.NextOrContext(ctx, func() { gaveUp = true }) {
+			if !gaveUp {
+				errs = append(errs, ctx.Err())
+			}
+			return &Error{Attempts: attempt, Errors: errs}
+		}
+
+		err := f(ctx)
+		if err == nil {
+			return nil
+		}
+
+		attempt++
+		errs = append(errs, err)
+		if c.OnRetry != nil {
+			c.OnRetry(attempt, err)
+		}
+
+		if c.ShouldRetry != nil && !c.ShouldRetry(err) {
+			return &Error{Attempts: attempt, Errors: errs}
+		}
+	}
+}
+
+// Error is returned by Config.Run when it gives up. It reports every
+// error seen across all attempts, rather than only the last one.
+type Error struct {
+	Attempts int
+	Errors   []error
+}
+
+func (e *Error) Error() string {
+	parts := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		parts[i] = err.Error()
+	}
+	return fmt.Sprintf("gave up after %d attempt(s): %s", e.Attempts, strings.Join(parts, "; "))
+}
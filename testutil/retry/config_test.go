@@ -0,0 +1,136 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type permanentErr struct{ error }
+
+func TestConfig_Run_succeedsEventually(t *testing.T) {
+	attempts := 0
+	cfg := Config{Retryer: &Counter{Count: 5}}
+
+	err := cfg.Run(context.Background(), func(ctx context.Context) error {
+		attempts++
+		if attempts < 3 {
+			return errors.New("transient")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if attempts != 3 {
+		t.Fatalf("expected 3 attempts, got %d", attempts)
+	}
+}
+
+func TestConfig_Run_stopsOnPermanentError(t *testing.T) {
+	attempts := 0
+	cfg := Config{
+		Retryer: &Counter{Count: 5},
+		ShouldRetry: func(err error) bool {
+			_, permanent := err.(permanentErr)
+			return !permanent
+		},
+	}
+
+	err := cfg.Run(context.Background(), func(ctx context.Context) error {
+		attempts++
+		return permanentErr{errors.New("unauthorized")}
+	})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if attempts != 1 {
+		t.Fatalf("expected to stop after the first permanent error, got %d attempts", attempts)
+	}
+}
+
+func TestConfig_Run_givesUpAfterRetryerExhausted(t *testing.T) {
+	cfg := Config{Retryer: &Counter{Count: 2}}
+
+	attempts := 0
+	err := cfg.Run(context.Background(), func(ctx context.Context) error {
+		attempts++
+		return errors.New("still failing")
+	})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	retryErr, ok := err.(*Error)
+	if !ok {
+		t.Fatalf("expected *Error, got %T", err)
+	}
+	if retryErr.Attempts != attempts {
+		t.Fatalf("expected Attempts to match the number of calls, got %d vs %d", retryErr.Attempts, attempts)
+	}
+	if len(retryErr.Errors) != attempts {
+		t.Fatalf("expected one recorded error per attempt, got %d", len(retryErr.Errors))
+	}
+}
+
+func TestConfig_Run_onRetryCalledPerAttempt(t *testing.T) {
+	var seen []int
+	cfg := Config{
+		Retryer: &Counter{Count: 3},
+		OnRetry: func(attempt int, err error) { seen = append(seen, attempt) },
+	}
+
+	_ = cfg.Run(context.Background(), func(ctx context.Context) error {
+		return errors.New("nope")
+	})
+
+	if len(seen) != 3 {
+		t.Fatalf("expected OnRetry to be called once per failed attempt (3, matching Counter.Count), got %d", len(seen))
+	}
+}
+
+// TestConfig_Run_waitsBeforeFirstRetry guards against Run calling f a
+// second time without ever waiting: a fresh Retryer's first-ever
+// NextOrContext call always starts its clock and returns immediately, so
+// Run must gate every attempt, including the first, through the Retryer
+// for the wait between attempt 1 and attempt 2 to actually happen.
+func TestConfig_Run_waitsBeforeFirstRetry(t *testing.T) {
+	clock := NewMockClock()
+	backoff := &Backoff{
+		InitialInterval: time.Second,
+		MaxInterval:     time.Second,
+		Multiplier:      1,
+		MaxElapsed:      time.Hour,
+		Clock:           clock,
+	}
+	cfg := Config{Retryer: backoff}
+
+	attempts := 0
+	done := make(chan error, 1)
+	go func() {
+		done <- cfg.Run(context.Background(), func(ctx context.Context) error {
+			attempts++
+			if attempts < 2 {
+				return errors.New("transient")
+			}
+			return nil
+		})
+	}()
+
+	if !clock.BlockUntil(1, time.Second) {
+		t.Fatal("expected Run to wait on a ticker before its first retry")
+	}
+	if attempts != 1 {
+		t.Fatalf("expected f to have been called exactly once before the wait, got %d", attempts)
+	}
+	clock.Advance(time.Second)
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("expected success, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after the mock clock advanced")
+	}
+}
@@ -28,7 +28,9 @@ package retry
 
 import (
 	"bytes"
+	"context"
 	"fmt"
+	"math/rand"
 	"runtime"
 	"strings"
 	"sync"
@@ -80,11 +82,20 @@ func decorate(s string) string {
 }
 
 func Run(t *testing.T, f func(r *R)) {
-	run(OneSec(), t, f)
+	run(context.Background(), OneSec(), t, f)
 }
 
 func RunWith(r Retryer, t *testing.T, f func(r *R)) {
-	run(r, t, f)
+	run(context.Background(), r, t, f)
+}
+
+// RunWithContext behaves like RunWith, but stops retrying as soon as ctx is
+// done, even if the Retryer itself would otherwise still wait or retry.
+// This lets a parent test cancellation or -timeout abort tests that wrap
+// long-running agents promptly, instead of sleeping through the Retryer's
+// own Wait.
+func RunWithContext(ctx context.Context, r Retryer, t *testing.T, f func(r *R)) {
+	run(ctx, r, t, f)
 }
 
 func dedup(a []string) string {
@@ -106,7 +117,7 @@ func dedup(a []string) string {
 	return string(b.Bytes())
 }
 
-func run(r Retryer, t *testing.T, f func(r *R)) {
+func run(ctx context.Context, r Retryer, t *testing.T, f func(r *R)) {
 	rr := &R{}
 	fail := func() {
 		out := dedup(rr.output)
@@ -115,7 +126,7 @@ func run(r Retryer, t *testing.T, f func(r *R)) {
 		}
 		t.FailNow()
 	}
-	for r.NextOr(fail) {
+	for r.NextOrContext(ctx, fail) {
 		var wg sync.WaitGroup
 		wg.Add(1)
 		go func() {
@@ -141,12 +152,33 @@ func ThreeTimes() *Counter {
 	return &Counter{Count: 3, Wait: 25 * time.Millisecond}
 }
 
+// ExpBackoff returns a Backoff configured with sensible defaults for flaky
+// integration tests: a 50ms initial wait growing by 1.5x up to a 2s cap,
+// jittered by 50%, giving up after 30s total elapsed.
+func ExpBackoff() *Backoff {
+	return &Backoff{
+		InitialInterval:     50 * time.Millisecond,
+		MaxInterval:         2 * time.Second,
+		Multiplier:          1.5,
+		RandomizationFactor: 0.5,
+		MaxElapsed:          30 * time.Second,
+	}
+}
+
 // Retryer provides an interface for repeating operations
 // until they succeed or an exit condition is met.
 type Retryer interface {
 	// NextOr returns true if the operation should be repeated.
 	// Otherwise, it calls fail and returns false.
 	NextOr(fail func()) bool
+
+	// NextOrContext behaves like NextOr, but while it waits between
+	// attempts it also returns false, without calling fail, as soon as ctx
+	// is done. This lets callers wrapping a long-running agent bail out
+	// immediately on cancellation instead of sleeping through the remaining
+	// wait. ctx is not checked outside that wait: the first call, which
+	// never waits, returns true even if ctx is already done.
+	NextOrContext(ctx context.Context, fail func()) bool
 }
 
 // Counter repeats an operation a given number of
@@ -155,16 +187,37 @@ type Counter struct {
 	Count int
 	Wait  time.Duration
 
+	// Clock is used to wait between attempts. Defaults to the real
+	// wall clock; tests can substitute a MockClock to avoid sleeping.
+	Clock Clock
+
 	count int
 }
 
+func (r *Counter) clock() Clock {
+	if r.Clock != nil {
+		return r.Clock
+	}
+	return defaultClock
+}
+
 func (r *Counter) NextOr(fail func()) bool {
+	return r.NextOrContext(context.Background(), fail)
+}
+
+func (r *Counter) NextOrContext(ctx context.Context, fail func()) bool {
 	if r.count == r.Count {
 		fail()
 		return false
 	}
 	if r.count > 0 {
-		time.Sleep(r.Wait)
+		t := r.clock().NewTicker(r.Wait)
+		select {
+		case <-t.C():
+		case <-ctx.Done():
+			t.Stop()
+			return false
+		}
 	}
 	r.count++
 	return true
@@ -176,20 +229,114 @@ type Timer struct {
 	Timeout time.Duration
 	Wait    time.Duration
 
+	// Clock provides Now and the wait between attempts. Defaults to the
+	// real wall clock; tests can substitute a MockClock to avoid
+	// sleeping through Timeout and Wait.
+	Clock Clock
+
 	// stop is the timeout deadline.
 	// Set on the first invocation of Next().
 	stop time.Time
 }
 
+func (r *Timer) clock() Clock {
+	if r.Clock != nil {
+		return r.Clock
+	}
+	return defaultClock
+}
+
 func (r *Timer) NextOr(fail func()) bool {
+	return r.NextOrContext(context.Background(), fail)
+}
+
+func (r *Timer) NextOrContext(ctx context.Context, fail func()) bool {
 	if r.stop.IsZero() {
-		r.stop = time.Now().Add(r.Timeout)
+		r.stop = r.clock().Now().Add(r.Timeout)
+		return true
+	}
+	if r.clock().Now().After(r.stop) {
+		fail()
+		return false
+	}
+	t := r.clock().NewTicker(r.Wait)
+	select {
+	case <-t.C():
+	case <-ctx.Done():
+		t.Stop()
+		return false
+	}
+	return true
+}
+
+// Backoff repeats an operation with an exponentially growing wait between
+// subsequent operations, jittered to avoid synchronized retries, until
+// MaxElapsed has passed since the first call. This is a good fit for
+// flaky integration tests that currently race against fixed ticks under
+// load: the wait starts short but keeps growing instead of hammering a
+// still-booting agent on a constant interval.
+type Backoff struct {
+	InitialInterval     time.Duration
+	MaxInterval         time.Duration
+	Multiplier          float64
+	RandomizationFactor float64
+	MaxElapsed          time.Duration
+
+	// Clock provides Now and the wait between attempts. Defaults to the
+	// real wall clock; tests can substitute a MockClock to avoid
+	// sleeping through MaxElapsed.
+	Clock Clock
+
+	current time.Duration
+	start   time.Time
+}
+
+func (r *Backoff) clock() Clock {
+	if r.Clock != nil {
+		return r.Clock
+	}
+	return defaultClock
+}
+
+func (r *Backoff) NextOr(fail func()) bool {
+	return r.NextOrContext(context.Background(), fail)
+}
+
+func (r *Backoff) NextOrContext(ctx context.Context, fail func()) bool {
+	if r.start.IsZero() {
+		r.start = r.clock().Now()
+		r.current = r.InitialInterval
 		return true
 	}
-	if time.Now().After(r.stop) {
+	if r.clock().Now().Sub(r.start) > r.MaxElapsed {
 		fail()
 		return false
 	}
-	time.Sleep(r.Wait)
+
+	wait := r.jittered(r.current)
+	r.current = time.Duration(float64(r.current) * r.Multiplier)
+	if r.current > r.MaxInterval {
+		r.current = r.MaxInterval
+	}
+
+	t := r.clock().NewTicker(wait)
+	select {
+	case <-t.C():
+	case <-ctx.Done():
+		t.Stop()
+		return false
+	}
 	return true
 }
+
+// jittered returns d adjusted by +/- RandomizationFactor*d, sampled
+// uniformly at random.
+func (r *Backoff) jittered(d time.Duration) time.Duration {
+	if r.RandomizationFactor <= 0 {
+		return d
+	}
+	delta := r.RandomizationFactor * float64(d)
+	min := float64(d) - delta
+	max := float64(d) + delta
+	return time.Duration(min + rand.Float64()*(max-min))
+}
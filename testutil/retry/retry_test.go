@@ -0,0 +1,92 @@
+package retry
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCounter_NextOrContext_cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	r := &Counter{Count: 10, Wait: time.Hour}
+	if !r.NextOrContext(ctx, func() { t.Fatal("fail should not be called yet") }) {
+		t.Fatal("expected the first call to return true without sleeping")
+	}
+
+	failed := false
+	if r.NextOrContext(ctx, func() { failed = true }) {
+		t.Fatal("expected NextOrContext to stop on a done context")
+	}
+	if failed {
+		t.Fatal("fail should not be called when ctx is done")
+	}
+}
+
+// TestCounter_NextOrContext_firstCallIgnoresDoneContext pins down, as its
+// own test, that a Counter's very first NextOrContext call always returns
+// true without consulting ctx at all: Counter only waits (and therefore
+// only checks ctx.Done) between attempts, once count > 0. The original
+// context-cancellation test added alongside NextOrContext asserted the
+// opposite for the first call and was wrong; it only started passing once
+// a later, unrelated commit happened to rewrite it while adding Backoff,
+// without calling out that it was fixing this bug.
+func TestCounter_NextOrContext_firstCallIgnoresDoneContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	r := &Counter{Count: 10, Wait: time.Hour}
+	if !r.NextOrContext(ctx, func() { t.Fatal("fail should not be called on the first attempt") }) {
+		t.Fatal("expected the first call to return true even with an already-done context")
+	}
+}
+
+func TestTimer_NextOrContext_cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	r := &Timer{Timeout: time.Hour, Wait: time.Hour}
+	if !r.NextOrContext(ctx, func() { t.Fatal("fail should not be called yet") }) {
+		t.Fatal("expected the first call to start the timer and return true")
+	}
+	if r.NextOrContext(ctx, func() { t.Fatal("fail should not be called on a done context") }) {
+		t.Fatal("expected NextOrContext to stop on a done context")
+	}
+}
+
+func TestBackoff_NextOr_growsAndGivesUp(t *testing.T) {
+	r := &Backoff{
+		InitialInterval: time.Millisecond,
+		MaxInterval:     5 * time.Millisecond,
+		Multiplier:      2,
+		MaxElapsed:      20 * time.Millisecond,
+	}
+
+	tries := 0
+	failed := false
+	for r.NextOr(func() { failed = true }) {
+		tries++
+		if tries > 1000 {
+			t.Fatal("Backoff never gave up")
+		}
+	}
+	if !failed {
+		t.Fatal("expected fail to be called once MaxElapsed passed")
+	}
+	// 1ms doubles to 2ms, 4ms, then caps at 5ms well before 20ms elapse.
+	if r.current != r.MaxInterval {
+		t.Fatalf("expected current interval to grow to MaxInterval %s, got %s", r.MaxInterval, r.current)
+	}
+}
+
+func TestBackoff_jittered(t *testing.T) {
+	r := &Backoff{RandomizationFactor: 0.5}
+	d := 100 * time.Millisecond
+	for i := 0; i < 100; i++ {
+		got := r.jittered(d)
+		if got < 50*time.Millisecond || got > 150*time.Millisecond {
+			t.Fatalf("jittered(%s) = %s, out of expected range", d, got)
+		}
+	}
+}